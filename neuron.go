@@ -4,10 +4,34 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type nstate uint8
+// AxonBuffer - capacity of each axon/dendrite channel created by Link*,
+//   LinkAt and LinkPriority. Buffering lets a fire step drain into a slow
+//   downstream neuron without blocking the sender; see Neuron.Dropped for
+//   what happens once a channel's buffer is full.
+var AxonBuffer = 16
+
+// synapse - a signal carried on a dendrite/axon channel: impulse is the
+//   raw magnitude from Fire or an upstream spike, weight is the sending
+//   axon's configured weight for that connection (see SetWeight), applied
+//   by the receiver when integrating into sigma.
+type synapse struct {
+	impulse int
+	weight  int
+}
+
+// nstate - a Neuron's lifecycle state. Stored as int32 rather than the more
+//   natural uint8 so getState/setState can use sync/atomic: n.state is
+//   written both by whoever starts the neuron (Run) and, once it's
+//   running, by the neuron's own goroutine (on "stop"), while being read
+//   from arbitrary other goroutines (Fire, SetWeight, wireAxon, ...) with
+//   no other synchronization between the two.
+type nstate int32
 
 const (
 	stopped nstate = iota
@@ -28,8 +52,24 @@ func (e nstate) String() string {
 	return nstates[e]
 }
 
+// cntlMsg - control message sent on a neuron's cntlChan. cmd selects which
+//   of the other fields, if any, are meaningful:
+//     "status"/"stop"   - no extra fields
+//     "newDendrite"     - newDendrite is a channel to add as a dendrite input
+//     "newAxon"         - newAxon is a channel to add as an axon output
+//     "dropPeer"        - peer is a previously wired neuron to unwire
+//   peer identifies the neuron the channel leads to/from, so the receiving
+//   goroutine can keep dendritePeers/axonPeers in step for Unlink lookups.
+//   priority carries the dendrite priority for "newDendrite" (see LinkPriority).
 type cntlMsg struct {
-	cmd string
+	cmd         string
+	newDendrite chan synapse
+	newAxon     chan synapse
+	peer        *Neuron
+	priority    int
+	weight      int           // for "setWeight"
+	leak        float64       // for "setLeak"
+	refractory  time.Duration // for "setRefractory"
 }
 
 var (
@@ -42,16 +82,36 @@ var (
 //   its own Go routine (when in running state)
 type Neuron struct {
 	cntlChan      chan cntlMsg     // input from central neuron controller
-	dendriteChans []chan int       // dendrite is input from other neurons
-	axonChans     []chan int       // axon is output to other neurons
+	dendriteChans []chan synapse   // dendrite is input from other neurons
+	dendritePeers []*Neuron        // neuron at the other end of each dendriteChans entry
+	dendritePriority []int         // priority of each dendriteChans entry, see LinkPriority
+	axonChans     []chan synapse   // axon is output to other neurons
+	axonPeers     []*Neuron        // neuron at the other end of each axonChans entry
+	axonWeight    []int            // weight applied by the receiver of each axonChans entry, see SetWeight
 	connected     bool             // connected to other neurons
-	state         nstate           // can be: stopped, running, paused
+	state         nstate           // can be: stopped, running, paused; read/written via getState/setState
 	sigma         int              // sum of dendrite signals
 	threshold     int              // level required for axon output
-	lastInChan    chan int         // last dendrite chan received
-	waitForChan   chan struct{}    // wiat for quiet neuron (no activity over time)
-	tickChan      <-chan time.Time // timer chan 
+	leak          float64          // sigma is multiplied by this on every tick, see SetLeak
+	refractoryPeriod time.Duration // how long after a spike axon output is suppressed, see SetRefractory
+	refractoryUntil  time.Time     // sigma crossing threshold before this time will not fire
+	lastInChan    chan synapse     // last dendrite chan received
+	cond          *sync.Cond       // guards activity and broadcasts when it quiesces, see WaitForQuiet
+	tickChan      <-chan time.Time // timer chan
 	activity      int              // number of messages received per second
+	dropped       uint64           // axon impulses dropped because a downstream buffer was full, see Dropped
+}
+
+// getState - read this neuron's lifecycle state. Safe to call from any
+//   goroutine; see the nstate doc comment for why this needs to be atomic.
+func (n *Neuron) getState() nstate {
+	return nstate(atomic.LoadInt32((*int32)(&n.state)))
+}
+
+// setState - set this neuron's lifecycle state. Safe to call from any
+//   goroutine; see the nstate doc comment for why this needs to be atomic.
+func (n *Neuron) setState(s nstate) {
+	atomic.StoreInt32((*int32)(&n.state), int32(s))
 }
 
 // New - creates a new neuron in the stopped state
@@ -59,12 +119,14 @@ func New(threshold int) *Neuron {
 	n := new(Neuron)
 	n.cntlChan = make(chan cntlMsg)
 	cntlChans = append(cntlChans, n.cntlChan)
+	n.cond = sync.NewCond(new(sync.Mutex))
 	n.connected = false
-	n.state = stopped
+	n.setState(stopped)
 	neurons = append(neurons, n)
 	n.tickChan = time.After(time.Second)
-	n.threshold = threshold	
-	n.activity++   // count creation as activity, so WaitForQuiet() will work 
+	n.threshold = threshold
+	n.leak = 1.0 // no decay until SetLeak says otherwise
+	n.activity++   // count creation as activity, so WaitForQuiet() will work
 	return n
 }
 
@@ -82,8 +144,8 @@ func NewNeurons(num int, threshold int) []*Neuron {
 func (n *Neuron) status() string {
 	var s string
 	s = fmt.Sprintf("\nNeuron: %p\n  State..........: %v\n  Dendrite inputs: %d\n  Axon outputs...: %d\n"+
-		"  Connected......: %v\n  Sigma..........: %d\n  Threshold......: %d",
-		n, n.state, len(n.dendriteChans), len(n.axonChans), n.connected, n.sigma, n.threshold)
+		"  Connected......: %v\n  Sigma..........: %d\n  Threshold......: %d\n  Dropped........: %d",
+		n, n.getState(), len(n.dendriteChans), len(n.axonChans), n.connected, n.sigma, n.threshold, n.Dropped())
 	return s
 }
 
@@ -102,46 +164,169 @@ func Status() {
 	}
 }
 
-// Link - connect an axom channel of a neuron to a dendrite channel of a neuron
+// Link - connect an axom channel of a neuron to a dendrite channel of a
+//   neuron. Goes through wireAxon/wireDendrite like LinkAt/LinkPriority, so
+//   it's also safe to call once either neuron is already running.
 func (n *Neuron) Link(to *Neuron) *Neuron {
-	a := make(chan int)
-	to.dendriteChans = append(to.dendriteChans, a)
-	to.connected = true
-	n.axonChans = append(n.axonChans, a)
-	n.connected = true
+	a := make(chan synapse, AxonBuffer)
+	n.wireAxon(to, a)
+	to.wireDendrite(n, a, 0)
+	return to
+}
+
+// LinkPriority - like LinkAt, but the new dendrite on to is marked with
+//   priority for to's select loop: each iteration scans the dendrites with
+//   priority > 0, highest first, before falling through to the full
+//   blocking select over control, tick and dendrites. This lets e.g. an
+//   inhibitory synapse preempt integration of a normal one that arrives in
+//   the same tick. Like Wire/LinkAt, this only touches cntlChan once the
+//   neuron on that side is actually running; see wireAxon/wireDendrite.
+func (n *Neuron) LinkPriority(to *Neuron, priority int) *Neuron {
+	a := make(chan synapse, AxonBuffer)
+	n.wireAxon(to, a)
+	to.wireDendrite(n, a, priority)
 	return to
 }
 
-// LinkManyToOne - connect an axom channel of many neurons to dendrite channels of a neuron
+// LinkManyToOne - connect an axom channel of many neurons to dendrite
+//   channels of a neuron. Goes through wireAxon/wireDendrite like Link, so
+//   it's also safe to call once any of the neurons involved are running.
 func (n *Neuron) LinkManyToOne(many []*Neuron) *Neuron {
 	for _, v := range many {
-		a := make(chan int)
-		v.axonChans = append(v.axonChans, a)
-		v.connected = true
-		n.dendriteChans = append(n.dendriteChans, a)
-		n.connected = true
+		a := make(chan synapse, AxonBuffer)
+		v.wireAxon(n, a)
+		n.wireDendrite(v, a, 0)
 	}
 	return n
 }
 
-// LinkOneToMany - connect an axom channel of one neuron to dendrite channels of many neurons
+// LinkOneToMany - connect an axom channel of one neuron to dendrite
+//   channels of many neurons. Goes through wireAxon/wireDendrite like Link,
+//   so it's also safe to call once any of the neurons involved are running.
 func (n *Neuron) LinkOneToMany(many []*Neuron) *Neuron {
 	for _, v := range many {
-		a := make(chan int)
-		v.connected = true
+		a := make(chan synapse, AxonBuffer)
+		n.wireAxon(v, a)
+		v.wireDendrite(n, a, 0)
+	}
+	return n
+}
+
+// SetWeight - set the weight applied to impulses sent on this neuron's
+//   axon to to. Takes effect from the next spike onward. Once the neuron
+//   is running, axonWeight is only safe to mutate from its own goroutine,
+//   so this goes through the cntlMsg control protocol like LinkAt/Unlink.
+func (n *Neuron) SetWeight(to *Neuron, w int) {
+	if n.getState() != running {
+		for i, p := range n.axonPeers {
+			if p == to {
+				n.axonWeight[i] = w
+			}
+		}
+		return
+	}
+	n.cntlChan <- cntlMsg{cmd: "setWeight", peer: to, weight: w}
+}
+
+// SetLeak - set the factor sigma is multiplied by on every tick, modelling
+//   the leak of a leaky integrate-and-fire neuron. 1.0 (the default) means
+//   no decay; 0 means sigma resets to zero every tick.
+func (n *Neuron) SetLeak(factor float64) {
+	if n.getState() != running {
+		n.leak = factor
+		return
+	}
+	n.cntlChan <- cntlMsg{cmd: "setLeak", leak: factor}
+}
+
+// SetRefractory - set how long after a spike this neuron suppresses axon
+//   output. Dendrite input still accumulates into sigma during this
+//   period, it just cannot trigger another spike until it elapses.
+func (n *Neuron) SetRefractory(d time.Duration) {
+	if n.getState() != running {
+		n.refractoryPeriod = d
+		return
+	}
+	n.cntlChan <- cntlMsg{cmd: "setRefractory", refractory: d}
+}
+
+// LinkAt - dynamically connect this neuron's axon to to's dendrite while
+//   either or both may already be running under StartAllNeurons. Like
+//   Link, this goes through the cntlMsg control protocol once a neuron is
+//   running, so dendriteChans and axonChans are only ever mutated by their
+//   owning goroutine; see wireAxon/wireDendrite.
+func (n *Neuron) LinkAt(to *Neuron) *Neuron {
+	Wire(n, to)
+	return to
+}
+
+// Unlink - reverse a Link/LinkAt between n and to, telling each side to
+//   drop whatever it has wired to the other (see dropPeer). Each side
+//   resolves its own axonPeers/dendritePeers, rather than Unlink reading
+//   them itself, since those are only safe to read from the owning
+//   goroutine once that neuron is running.
+func (n *Neuron) Unlink(to *Neuron) *Neuron {
+	n.dropPeer(to)
+	to.dropPeer(n)
+	return to
+}
+
+// Wire - connect from's axon to to's dendrite: a channel is created here
+//   and added directly to each neuron's axonChans/dendriteChans if that
+//   neuron isn't running yet, or handed to its own goroutine via the
+//   cntlMsg control protocol if it is, so a graph can be rewired from
+//   outside without a data race on either slice. See wireAxon/wireDendrite.
+func Wire(from, to *Neuron) {
+	a := make(chan synapse, AxonBuffer)
+	from.wireAxon(to, a)
+	to.wireDendrite(from, a, 0)
+}
+
+// wireAxon - add a as an axon channel to peer, directly if n isn't running
+//   yet (no goroutine exists to race with) or via cntlMsg if it is. Shared
+//   by Wire/LinkAt and LinkPriority.
+func (n *Neuron) wireAxon(peer *Neuron, a chan synapse) {
+	if n.getState() != running {
 		n.axonChans = append(n.axonChans, a)
+		n.axonPeers = append(n.axonPeers, peer)
+		n.axonWeight = append(n.axonWeight, 1)
 		n.connected = true
-		v.dendriteChans = append(v.dendriteChans, a)
+		return
 	}
-	return n
+	n.cntlChan <- cntlMsg{cmd: "newAxon", newAxon: a, peer: peer}
+}
+
+// wireDendrite - add a as a dendrite channel from peer at priority,
+//   directly if n isn't running yet or via cntlMsg if it is. Shared by
+//   Wire/LinkAt and LinkPriority.
+func (n *Neuron) wireDendrite(peer *Neuron, a chan synapse, priority int) {
+	if n.getState() != running {
+		n.dendriteChans = append(n.dendriteChans, a)
+		n.dendritePeers = append(n.dendritePeers, peer)
+		n.dendritePriority = append(n.dendritePriority, priority)
+		n.connected = true
+		return
+	}
+	n.cntlChan <- cntlMsg{cmd: "newDendrite", newDendrite: a, peer: peer, priority: priority}
+}
+
+// dropPeer - remove whichever axon or dendrite channel this neuron has
+//   wired to peer, directly if n isn't running yet or via cntlMsg if it
+//   is. Shared by Unlink.
+func (n *Neuron) dropPeer(peer *Neuron) {
+	if n.getState() != running {
+		n.dropChanTo(peer)
+		return
+	}
+	n.cntlChan <- cntlMsg{cmd: "dropPeer", peer: peer}
 }
 
 // Fire - send an impulse to a specific neuron
 func (n *Neuron) Fire(impulse int) *Neuron {
-	if n.state == running {
+	if n.getState() == running {
 		if len(n.dendriteChans) > 0 {
 			fireChan := n.dendriteChans[0]
-			fireChan <- impulse
+			fireChan <- synapse{impulse: impulse, weight: 1}
 		}
 	}
 	return n
@@ -150,23 +335,49 @@ func (n *Neuron) Fire(impulse int) *Neuron {
 // FireMultiple - send an impulse to specific neurons
 func (n *Neuron) FireMultiple(neurons []*Neuron, impulse int) *Neuron {
 	for _, m := range neurons {
-		if m.state == running {
+		if m.getState() == running {
 			if len(m.dendriteChans) > 0 {
 				fireChan := m.dendriteChans[0]
-				fireChan <- impulse
+				fireChan <- synapse{impulse: impulse, weight: 1}
 			}
 		}
 	}
 	return n
 }
 
-// WaitForQuiet - wait until this neuron becomes inactive for a time
+// WaitForQuiet - wait until this neuron's activity count is zero: returns
+//   at once if no impulse has arrived since its last tick, otherwise once
+//   the next tick resets the count (see quiesce). Safe
+//   to call from any number of goroutines, and any number of times, since
+//   it waits on a sync.Cond broadcast rather than a single-receiver channel.
 func (n *Neuron) WaitForQuiet() {
-	if n.waitForChan == nil {
-		n.waitForChan = make(chan struct{})
-		<-n.waitForChan
-	} else {
-		<-n.waitForChan
+	n.cond.L.Lock()
+	for n.activity > 0 {
+		n.cond.Wait()
+	}
+	n.cond.L.Unlock()
+}
+
+// WaitForQuietAll - wait until every defined neuron reports zero activity
+//   at the same moment: after waiting on each in turn, it checks them all
+//   again with their locks held together, and starts over if any has
+//   become active again in the meantime.
+func WaitForQuietAll() {
+	for {
+		for _, n := range neurons {
+			n.WaitForQuiet()
+		}
+		quiet := true
+		for _, n := range neurons {
+			n.cond.L.Lock()
+			quiet = quiet && n.activity == 0
+		}
+		for _, n := range neurons {
+			n.cond.L.Unlock()
+		}
+		if quiet {
+			return
+		}
 	}
 }
 
@@ -187,12 +398,145 @@ func StopAllNeurons() {
 	}
 }
 
+// fixedCases - number of reflect.SelectCase entries that precede the
+//   per-dendrite cases in the slice built by selectCases: cntlChan, tickChan
+const fixedCases = 2
+
+// selectCases - build the list of reflect.SelectCase entries for this
+//   neuron's control, tick and dendrite channels. Called once when the
+//   neuron starts running and again whenever the dendrite set changes,
+//   since reflect.Select needs the whole case list rebuilt in place.
+func (n *Neuron) selectCases() []reflect.SelectCase {
+	cases := make([]reflect.SelectCase, len(n.dendriteChans)+fixedCases)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.cntlChan)}
+	cases[1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.tickChan)}
+	for i, ch := range n.dendriteChans {
+		cases[i+fixedCases] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+	}
+	return cases
+}
+
+// dropChanTo - remove whichever of this neuron's axon or dendrite channels
+//   leads to peer. Called either from the neuron's own goroutine in
+//   response to a "dropPeer" cntlMsg, or directly by dropPeer when the
+//   neuron isn't running yet. Reports whether the dendrite set changed,
+//   since that is what requires the select cases to be rebuilt.
+func (n *Neuron) dropChanTo(peer *Neuron) bool {
+	for i, p := range n.axonPeers {
+		if p == peer {
+			n.axonChans = append(n.axonChans[:i], n.axonChans[i+1:]...)
+			n.axonPeers = append(n.axonPeers[:i], n.axonPeers[i+1:]...)
+			n.axonWeight = append(n.axonWeight[:i], n.axonWeight[i+1:]...)
+			return false
+		}
+	}
+	for i, p := range n.dendritePeers {
+		if p == peer {
+			n.removeDendriteAt(i)
+			return true
+		}
+	}
+	return false
+}
+
+// removeDendriteAt - drop the dendrite at idx from dendriteChans and its
+//   parallel dendritePeers/dendritePriority entries. Called from the
+//   neuron's own goroutine, or via dropChanTo before the neuron is running.
+func (n *Neuron) removeDendriteAt(idx int) {
+	n.dendriteChans = append(n.dendriteChans[:idx], n.dendriteChans[idx+1:]...)
+	n.dendritePeers = append(n.dendritePeers[:idx], n.dendritePeers[idx+1:]...)
+	n.dendritePriority = append(n.dendritePriority[:idx], n.dendritePriority[idx+1:]...)
+}
+
+// highPriorityScan - probe the dendrites with priority > 0 one at a time,
+//   highest priority first, each via its own non-blocking single-case
+//   select, and return the first one that already has an impulse waiting.
+//   Bundling every positive-priority dendrite into a single reflect.Select
+//   would only distinguish "priority > 0" from "priority == 0": per
+//   reflect.Select's own doc, it "makes a uniform pseudo-random choice"
+//   among whichever cases are ready, so it can't prefer priority 9 over
+//   priority 1. Probing in descending-priority order instead guarantees
+//   that, so e.g. an inhibitory synapse at priority 9 preempts a normal
+//   one at priority 1 that arrived in the same tick.
+func (n *Neuron) highPriorityScan() (di int, value reflect.Value, ok bool, found bool) {
+	idx := make([]int, 0, len(n.dendritePriority))
+	for i, p := range n.dendritePriority {
+		if p > 0 {
+			idx = append(idx, i)
+		}
+	}
+	sort.Slice(idx, func(a, b int) bool {
+		return n.dendritePriority[idx[a]] > n.dendritePriority[idx[b]]
+	})
+	for _, i := range idx {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.dendriteChans[i])},
+			{Dir: reflect.SelectDefault},
+		}
+		chosen, v, recvOK := reflect.Select(cases)
+		if chosen == 0 {
+			return i, v, recvOK, true
+		}
+	}
+	return 0, reflect.Value{}, false, false
+}
+
+// integrate - add value to sigma, triggering a spike if that crosses
+//   threshold outside the refractory period (see SetRefractory). A spike
+//   fires every axon and resets sigma to its resting potential of zero.
+func (n *Neuron) integrate(value int) {
+	n.sigma += value
+	if n.sigma > n.threshold && !time.Now().Before(n.refractoryUntil) {
+		for i, m := range n.axonChans {
+			select {
+			case m <- synapse{impulse: 1, weight: n.axonWeight[i]}:
+			default: // downstream buffer full, don't stall the fire step
+				atomic.AddUint64(&n.dropped, 1)
+			}
+		}
+		n.refractoryUntil = time.Now().Add(n.refractoryPeriod)
+		n.sigma = 0
+	}
+}
+
+// decay - apply this tick's leak factor to sigma (see SetLeak), modelling
+//   the passive decay of a leaky integrate-and-fire neuron between spikes.
+//   Called once per tick from Run.
+func (n *Neuron) decay() {
+	n.sigma = int(float64(n.sigma) * n.leak)
+}
+
+// Dropped - number of axon impulses dropped because a downstream neuron's
+//   dendrite buffer was full. Safe to call concurrently with firing.
+func (n *Neuron) Dropped() uint64 {
+	return atomic.LoadUint64(&n.dropped)
+}
+
+// bumpActivity - record that a dendrite delivered an impulse this tick,
+//   guarded by the same mutex WaitForQuiet waits on.
+func (n *Neuron) bumpActivity() {
+	n.cond.L.Lock()
+	n.activity++
+	n.cond.L.Unlock()
+}
+
+// quiesce - called on each tick: if activity has accumulated since the
+//   last tick, reset it to zero and broadcast, waking any goroutines
+//   blocked in WaitForQuiet/WaitForQuietAll.
+func (n *Neuron) quiesce() {
+	n.cond.L.Lock()
+	if n.activity > 0 {
+		n.activity = 0
+		n.cond.Broadcast()
+	}
+	n.cond.L.Unlock()
+}
+
 // Run - start all neuron channels executing
 func (n *Neuron) Run() error {
 	var err error
-	var cmd string
 
-	if n.state == stopped {
+	if n.getState() == stopped {
 		go func() {
 			/*
 				A dynamic select statement using the reflect package.
@@ -208,45 +552,91 @@ func (n *Neuron) Run() error {
 				select on, the direction of the operation, and a value to send in the
 				case of a send operation.
 			*/
-			const fixedCases = 2
-			cases := make([]reflect.SelectCase, len(n.dendriteChans)+fixedCases)
-			cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.cntlChan)}
-			cases[1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.tickChan)}
-			for i, ch := range n.dendriteChans {
-				cases[i+fixedCases] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
-			}
-			chosen, value, _ /*ok*/ := reflect.Select(cases)
-			// ok will be true if the channel has not been closed.
-			if chosen == 0 { // cntlChan selected
-				cmd = value.String()
-				switch cmd {
-				case "status":
-					log.Printf("Status...%s", n.status())
-				case "stop":
-					log.Printf("Stopped neuron: %p", n)
-					return
-				}
-			} else if chosen == 1 { // timer chan selected
-				if n.activity > 0 {
-					if n.waitForChan != nil {
-						n.waitForChan <- struct{}{}
+			cases := n.selectCases()
+			for {
+				// Two-phase select: scan high-priority dendrites first,
+				// highest priority ready one wins, so e.g. an inhibitory
+				// synapse can preempt a normal one that arrives in the same
+				// tick. Only fall through to the full blocking select if
+				// none is ready.
+				if di, hvalue, hok, found := n.highPriorityScan(); found {
+					if !hok {
+						n.removeDendriteAt(di)
+						cases = n.selectCases()
+						continue
 					}
-					n.activity = 0
+					n.bumpActivity()
+					hmsg := hvalue.Interface().(synapse)
+					n.integrate(hmsg.impulse * hmsg.weight)
+					n.lastInChan = n.dendriteChans[di]
+					continue
 				}
-			} else { // dendrite chan selected
-				n.activity++
-				n.sigma += int(value.Int())
-				if n.sigma > n.threshold { // trigger the axom ?
-					for _, m := range n.axonChans {
-						m <- 1
+
+				chosen, value, ok := reflect.Select(cases)
+				// ok will be false if the channel has been closed, rather than
+				// having actually delivered a value.
+				if chosen == 0 { // cntlChan selected
+					msg := value.Interface().(cntlMsg)
+					switch msg.cmd {
+					case "status":
+						log.Printf("Status...%s", n.status())
+					case "stop":
+						log.Printf("Stopped neuron: %p", n)
+						n.setState(stopped)
+						n.cond.L.Lock()
+						n.activity = 0
+						n.cond.Broadcast()
+						n.cond.L.Unlock()
+						return
+					case "newDendrite":
+						n.dendriteChans = append(n.dendriteChans, msg.newDendrite)
+						n.dendritePeers = append(n.dendritePeers, msg.peer)
+						n.dendritePriority = append(n.dendritePriority, msg.priority)
+						n.connected = true
+						cases = n.selectCases()
+					case "newAxon":
+						n.axonChans = append(n.axonChans, msg.newAxon)
+						n.axonPeers = append(n.axonPeers, msg.peer)
+						n.axonWeight = append(n.axonWeight, 1)
+						n.connected = true
+					case "dropPeer":
+						if n.dropChanTo(msg.peer) {
+							cases = n.selectCases()
+						}
+					case "setWeight":
+						for i, p := range n.axonPeers {
+							if p == msg.peer {
+								n.axonWeight[i] = msg.weight
+							}
+						}
+					case "setLeak":
+						n.leak = msg.leak
+					case "setRefractory":
+						n.refractoryPeriod = msg.refractory
 					}
+				} else if chosen == 1 { // timer chan selected
+					n.quiesce()
+					n.decay()
+					// time.After is single-shot, so the tick must be rearmed
+					// and its case entry rebuilt to reference the new channel.
+					n.tickChan = time.After(time.Second)
+					cases[1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(n.tickChan)}
+				} else { // dendrite chan selected
+					idx := chosen - fixedCases
+					if !ok {
+						// dendrite channel was closed: drop it and rebuild cases.
+						n.removeDendriteAt(idx)
+						cases = n.selectCases()
+						continue
+					}
+					n.bumpActivity()
+					msg := value.Interface().(synapse)
+					n.integrate(msg.impulse * msg.weight)
+					n.lastInChan = n.dendriteChans[idx]
 				}
 			}
-			if len(n.dendriteChans) > 0 {
-				n.lastInChan = n.dendriteChans[chosen]
-			}
 		}()
-		n.state = running
+		n.setState(running)
 	}
 	return err
 }
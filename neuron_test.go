@@ -0,0 +1,280 @@
+package neuron
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWaitForQuietAll checks the package-level helper over a small set of
+// neurons. It deliberately runs first in this file: WaitForQuietAll loops
+// over every neuron ever created via New(), and a neuron that is never
+// Run() never ticks, so its activity would never quiesce and would hang
+// this test if some later test left one lying around first.
+func TestWaitForQuietAll(t *testing.T) {
+	a := New(1000000)
+	b := New(1000000)
+	a.Run()
+	b.Run()
+	defer func() {
+		a.cntlChan <- cntlMsg{cmd: "stop"}
+		b.cntlChan <- cntlMsg{cmd: "stop"}
+	}()
+
+	a.Fire(1)
+
+	done := make(chan struct{})
+	go func() {
+		WaitForQuietAll()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("WaitForQuietAll never returned")
+	}
+}
+
+// TestWaitForQuiet checks that WaitForQuiet, called while the neuron still
+// has activity pending from before its first tick, returns once that tick
+// resets the activity to zero.
+func TestWaitForQuiet(t *testing.T) {
+	n := New(1000000) // high threshold: firing won't trigger an axon send
+	n.Run()
+	defer func() { n.cntlChan <- cntlMsg{cmd: "stop"} }()
+
+	n.Fire(1)
+
+	done := make(chan struct{})
+	go func() {
+		n.WaitForQuiet()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("WaitForQuiet never returned after a tick should have quiesced the neuron")
+	}
+}
+
+// TestHighPriorityScanOrdering checks that, when dendrites at two different
+// positive priorities are both ready, highPriorityScan picks the higher one
+// rather than choosing uniformly at random among them. It builds a Neuron
+// by hand rather than via New(), so it leaves nothing in the package's
+// global neurons registry.
+func TestHighPriorityScanOrdering(t *testing.T) {
+	n := &Neuron{}
+	low := make(chan synapse, 1)
+	high := make(chan synapse, 1)
+	n.dendriteChans = []chan synapse{low, high}
+	n.dendritePriority = []int{1, 9}
+	low <- synapse{impulse: 1, weight: 1}
+	high <- synapse{impulse: 1, weight: 1}
+
+	di, _, ok, found := n.highPriorityScan()
+	if !found || !ok {
+		t.Fatalf("want a ready high-priority dendrite, found=%v ok=%v", found, ok)
+	}
+	if di != 1 {
+		t.Fatalf("want the priority-9 dendrite (index 1) picked ahead of priority-1, got index %d", di)
+	}
+}
+
+// TestLinkAtUnlinkBeforeRun reproduces the deadlock a reviewer found:
+// a.LinkAt(b) called before either neuron is running used to block forever
+// because Wire/Unlink sent unconditionally on a cntlChan nobody was reading.
+func TestLinkAtUnlinkBeforeRun(t *testing.T) {
+	a := New(1)
+	b := New(1)
+
+	done := make(chan struct{})
+	go func() {
+		a.LinkAt(b)
+		a.Unlink(b)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LinkAt/Unlink deadlocked when called before Run()")
+	}
+
+	if got := len(b.dendriteChans); got != 0 {
+		t.Fatalf("want Unlink to remove the dendrite LinkAt added, got %d still wired", got)
+	}
+}
+
+// TestLinkAtUnlinkWhileRunning exercises the same pair once both neurons
+// are already running, where LinkAt/Unlink go through the cntlMsg protocol.
+func TestLinkAtUnlinkWhileRunning(t *testing.T) {
+	a := New(1)
+	b := New(1)
+	a.Run()
+	b.Run()
+	defer func() {
+		a.cntlChan <- cntlMsg{cmd: "stop"}
+		b.cntlChan <- cntlMsg{cmd: "stop"}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		a.LinkAt(b)
+		a.Unlink(b)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LinkAt/Unlink deadlocked while both neurons were running")
+	}
+}
+
+// TestLinkAtAfterStop reproduces a second deadlock: the "stop" cntlMsg
+// handler used to return without clearing n.state back to stopped, so a
+// neuron that had been running and was then stopped looked permanently
+// "running" to LinkAt/Unlink/SetWeight/SetLeak/SetRefractory's state
+// checks, which then sent on a cntlChan nobody was draining anymore.
+func TestLinkAtAfterStop(t *testing.T) {
+	a := New(1)
+	b := New(1)
+	a.Run()
+	b.Run()
+	a.cntlChan <- cntlMsg{cmd: "stop"}
+	b.cntlChan <- cntlMsg{cmd: "stop"}
+	time.Sleep(50 * time.Millisecond) // give both goroutines time to actually exit
+
+	done := make(chan struct{})
+	go func() {
+		a.LinkAt(b)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LinkAt deadlocked after both neurons were stopped")
+	}
+}
+
+// TestDropped checks that firing past a downstream neuron's buffer
+// capacity is counted by Dropped rather than blocking the sender.
+func TestDropped(t *testing.T) {
+	upstream := New(0)
+	downstream := New(1 << 30) // high enough threshold that it never fires itself
+	trigger := New(0)
+	trigger.Link(upstream)    // gives upstream a dendriteChans[0] to Fire into
+	upstream.Link(downstream) // downstream.Run() is never called, so nobody drains it
+
+	upstream.Run()
+	defer func() { upstream.cntlChan <- cntlMsg{cmd: "stop"} }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for upstream.Dropped() == 0 && time.Now().Before(deadline) {
+		upstream.Fire(1) // sigma resets to 0 after every spike, so this fires every time
+	}
+
+	if got := upstream.Dropped(); got == 0 {
+		t.Fatalf("want Dropped() > 0 once downstream's buffer (cap %d) fills, got 0", AxonBuffer)
+	}
+}
+
+// TestSetWeightBeforeRun checks the direct-mutation path SetWeight takes
+// when the neuron isn't running yet.
+func TestSetWeightBeforeRun(t *testing.T) {
+	a := New(1)
+	b := &Neuron{}
+	a.Link(b)
+
+	a.SetWeight(b, 5)
+
+	if got := a.axonWeight[0]; got != 5 {
+		t.Fatalf("want axonWeight 5, got %d", got)
+	}
+}
+
+// TestSetWeightAppliesToFiredImpulse checks both that SetWeight's cntlMsg
+// path takes effect while the neuron is running, and that integrate sends
+// the configured weight on, rather than just acknowledging the call.
+func TestSetWeightAppliesToFiredImpulse(t *testing.T) {
+	trigger := &Neuron{}
+	u := New(0) // threshold 0: any positive impulse fires immediately
+	d := &Neuron{}
+	trigger.Link(u) // gives u a dendriteChans[0] to Fire into
+	u.Link(d)
+
+	u.Run()
+	defer func() { u.cntlChan <- cntlMsg{cmd: "stop"} }()
+
+	u.SetWeight(d, 7) // u is running, so this goes through cntlChan
+	u.Fire(1)
+
+	select {
+	case syn := <-d.dendriteChans[0]:
+		if syn.weight != 7 {
+			t.Fatalf("want weight 7 applied to the axon output after SetWeight, got %d", syn.weight)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("u never fired onto d")
+	}
+}
+
+// TestSetLeakBeforeRun checks the direct-mutation path SetLeak takes when
+// the neuron isn't running yet.
+func TestSetLeakBeforeRun(t *testing.T) {
+	n := New(1)
+	n.SetLeak(0.25)
+	if n.leak != 0.25 {
+		t.Fatalf("want leak 0.25, got %v", n.leak)
+	}
+}
+
+// TestDecay checks the per-tick leak arithmetic applied by decay.
+func TestDecay(t *testing.T) {
+	n := &Neuron{leak: 0.5, sigma: 10}
+	n.decay()
+	if n.sigma != 5 {
+		t.Fatalf("want sigma to decay to 5 with leak 0.5, got %d", n.sigma)
+	}
+}
+
+// TestSetRefractoryBeforeRun checks the direct-mutation path SetRefractory
+// takes when the neuron isn't running yet.
+func TestSetRefractoryBeforeRun(t *testing.T) {
+	n := New(1)
+	n.SetRefractory(5 * time.Second)
+	if n.refractoryPeriod != 5*time.Second {
+		t.Fatalf("want refractoryPeriod 5s, got %v", n.refractoryPeriod)
+	}
+}
+
+// TestIntegrateRefractory checks that a spike within the refractory period
+// is suppressed while sigma keeps accumulating, and that the very first
+// spike (refractoryUntil still its zero value) is not itself suppressed.
+func TestIntegrateRefractory(t *testing.T) {
+	n := &Neuron{threshold: 0, refractoryPeriod: time.Hour}
+	n.axonChans = []chan synapse{make(chan synapse, 1)}
+	n.axonWeight = []int{1}
+
+	n.integrate(1)
+	select {
+	case <-n.axonChans[0]:
+	default:
+		t.Fatal("want the first spike to fire")
+	}
+	if n.sigma != 0 {
+		t.Fatalf("want sigma reset to 0 after a spike, got %d", n.sigma)
+	}
+
+	n.integrate(1) // still > threshold, but now inside the hour-long refractory window
+	select {
+	case <-n.axonChans[0]:
+		t.Fatal("want the second spike suppressed by the refractory period")
+	default:
+	}
+	if n.sigma != 1 {
+		t.Fatalf("want sigma to keep accumulating during refractory, got %d", n.sigma)
+	}
+}